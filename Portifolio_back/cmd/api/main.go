@@ -2,94 +2,255 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/assets"
 	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/auth"
 	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/db"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/db/migrate"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/deploy"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/httpmw"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/logging"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/metrics"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/static"
+	"go.uber.org/zap"
 )
 
 func main() {
+	migrateCmd := flag.String("migrate", "", "run database migrations out-of-band instead of starting the server: up, down, or status")
+	flag.Parse()
+
 	_ = godotenv.Load(".env")
+
+	logger, err := logging.New(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		log.Fatalf("invalid LOG_LEVEL: %v", err)
+	}
+	defer logger.Sync()
+
 	ctx := context.Background()
 	pool, err := db.NewPool(ctx)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatal("connect to database", zap.Error(err))
+	}
+
+	migrator, err := migrate.New(pool, logger)
+	if err != nil {
+		logger.Fatal("load migrations", zap.Error(err))
+	}
+
+	if *migrateCmd != "" {
+		runMigrateCommand(ctx, migrator, *migrateCmd, logger)
+		return
 	}
 
-	r := setupRouter(ctx, pool)
+	if err := migrator.Up(ctx); err != nil {
+		logger.Fatal("apply migrations", zap.Error(err))
+	}
+
+	var draining atomic.Bool
+	r := setupRouter(ctx, pool, logger, &draining)
 	port := getPort()
 
-	log.Printf("Starting server on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, r))
+	srv := &http.Server{Addr: ":" + port, Handler: r}
+	go func() {
+		logger.Info("starting server", zap.String("port", port))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("listen", zap.Error(err))
+		}
+	}()
+
+	waitForShutdown(srv, pool, &draining, logger)
+}
+
+// waitForShutdown blocks until SIGINT/SIGTERM, then drains in-flight
+// requests (failing /readyz in the meantime) before closing the pgx pool.
+func waitForShutdown(srv *http.Server, pool *pgxpool.Pool, draining *atomic.Bool, logger *zap.Logger) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	draining.Store(true)
+	logger.Info("shutdown signal received, draining connections", zap.Duration("timeout", shutdownTimeout()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("graceful shutdown did not complete cleanly", zap.Error(err))
+	}
+	pool.Close()
+	logger.Info("shutdown complete")
+}
+
+// shutdownTimeout is how long waitForShutdown waits for in-flight requests to
+// finish before forcibly closing connections. Configurable via
+// SHUTDOWN_TIMEOUT (a Go duration string, e.g. "45s"); defaults to 30s.
+func shutdownTimeout() time.Duration {
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return 30 * time.Second
+}
+
+// runMigrateCommand services the -migrate CLI flag so migrations can be
+// applied, rolled back, or inspected out-of-band without starting the server.
+func runMigrateCommand(ctx context.Context, migrator *migrate.Migrator, cmd string, logger *zap.Logger) {
+	switch cmd {
+	case "up":
+		if err := migrator.Up(ctx); err != nil {
+			logger.Fatal("migrate up", zap.Error(err))
+		}
+	case "down":
+		if err := migrator.Down(ctx); err != nil {
+			logger.Fatal("migrate down", zap.Error(err))
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Fatal("migrate status", zap.Error(err))
+		}
+		for _, s := range statuses {
+			state := "pending"
+			switch {
+			case s.Drift:
+				state = "applied (checksum drift!)"
+			case s.Applied:
+				state = "applied"
+			}
+			logger.Info("migration status", zap.Int64("version", s.Version), zap.String("name", s.Name), zap.String("state", state))
+		}
+	default:
+		logger.Fatal("migrate: unknown command", zap.String("command", cmd))
+	}
 }
 
 // setupRouter configures and returns the Gin router with all routes and middleware.
-func setupRouter(ctx context.Context, pool *pgxpool.Pool) *gin.Engine {
-	r := gin.Default()
-	r.Use(gin.Logger(), gin.Recovery())
+func setupRouter(ctx context.Context, pool *pgxpool.Pool, logger *zap.Logger, draining *atomic.Bool) *gin.Engine {
+	r := gin.New()
+
+	if proxies := httpmw.SplitCSV(os.Getenv("TRUSTED_PROXIES")); len(proxies) > 0 {
+		if err := r.SetTrustedProxies(proxies); err != nil {
+			logger.Fatal("set trusted proxies", zap.Error(err))
+		}
+	} else {
+		_ = r.SetTrustedProxies(nil)
+	}
 
-	// Health check endpoint
-	r.GET("/health", healthCheckHandler)
+	r.Use(logging.RequestIDMiddleware(), logging.Middleware(logger), logging.Recovery(logger))
 
-	// Database schema initialization
-	ensureSchema(ctx, pool)
+	// Request metrics: per-route counts/latency/size plus pgxpool stats. This
+	// must wrap httpmw.Gzip() (registered below), not the other way around:
+	// Gzip buffers the body and only flushes it for real after the handler
+	// chain returns, so a middleware that reads c.Writer.Size() from inside
+	// Gzip's c.Next() would always see a stale, pre-flush size.
+	m := metrics.Setup("pedro", "api")
+	m.RegisterPool(pool)
+	r.Use(m.Middleware())
+	r.GET("/metrics", m.Handler(os.Getenv("METRICS_TOKEN")))
+
+	r.Use(httpmw.SecurityHeaders(), httpmw.CORS(os.Getenv("CORS_ALLOWED_ORIGINS")), httpmw.Gzip())
+
+	// Liveness/readiness probes. /healthz is a pure liveness check; /readyz
+	// additionally pings the database and fails while draining.
+	r.GET("/healthz", livenessHandler)
+	r.GET("/readyz", readinessHandler(pool, draining))
 
 	// API routes
 	auth.RegisterRoutes(r, wrap(pool))
 
 	// Configure static file serving for frontend SPA
-	configureStaticFiles(r)
+	spa := configureStaticFiles(r)
+
+	// Admin endpoint for hot-swapping the SPA bundle without a redeploy
+	deploy.NewManager(filepath.Join("static", "releases"), os.Getenv("ADMIN_DEPLOY_TOKEN"), spa).RegisterRoutes(r)
 
 	return r
 }
 
-// healthCheckHandler returns a simple health check response.
-func healthCheckHandler(c *gin.Context) {
+// livenessHandler reports the process is alive. It never checks downstream
+// dependencies, so a slow database can't make Kubernetes/Azure kill and
+// restart an otherwise-healthy pod.
+func livenessHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
-// configureStaticFiles sets up static file serving and SPA routing.
-// In production/Docker deployments, the frontend is built and served from ./static
-func configureStaticFiles(r *gin.Engine) {
-	const staticDir = "./static"
+// readinessHandler reports whether the server should keep receiving traffic:
+// it fails while draining during shutdown, and otherwise pings the pgxpool
+// with a short timeout so load balancers can route around a dead database.
+func readinessHandler(pool *pgxpool.Pool, draining *atomic.Bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if draining.Load() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"ok": false, "error": "shutting down"})
+			return
+		}
 
-	stat, err := os.Stat(staticDir)
-	if err != nil {
-		log.Printf("Static directory not found (%v), serving API only", err)
-		r.NoRoute(apiOnlyNoRouteHandler)
-		return
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+		if err := pool.Ping(ctx); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"ok": false, "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+// configureStaticFiles sets up static file serving and SPA routing.
+// In production the SPA is embedded into the binary at build time (see
+// embed.go); set APP_STATIC_DIR to serve straight from disk during frontend
+// development instead.
+func configureStaticFiles(r *gin.Engine) *static.Reloadable {
+	var fsys fs.FS
+	if dir := os.Getenv("APP_STATIC_DIR"); dir != "" {
+		log.Printf("Serving static files from disk: %s", dir)
+		fsys = os.DirFS(dir)
+	} else {
+		sub, err := fs.Sub(assets.Static, "static")
+		if err != nil {
+			log.Fatalf("static: embed subtree: %v", err)
+		}
+		fsys = sub
+		log.Printf("Serving static files from embedded SPA bundle")
 	}
 
-	if !stat.IsDir() {
-		log.Printf("Static path exists but is not a directory, serving API only")
+	handler, err := static.Handler(fsys)
+	if err != nil {
+		log.Printf("Static assets unavailable (%v), serving API only", err)
 		r.NoRoute(apiOnlyNoRouteHandler)
-		return
+		return static.NewReloadable(apiOnlyNoRouteHandler)
 	}
 
-	log.Printf("Serving static files from %s", staticDir)
-	r.Static("/assets", staticDir+"/assets")
-	r.StaticFile("/", staticDir+"/index.html")
-	r.StaticFile("/favicon.ico", staticDir+"/favicon.ico")
-	r.NoRoute(spaNoRouteHandler(staticDir))
+	spa := static.NewReloadable(handler)
+	r.NoRoute(spaNoRouteHandler(spa.Handler()))
+	return spa
 }
 
-// spaNoRouteHandler returns a handler for SPA client-side routing.
-// API routes get JSON 404, all other routes serve index.html.
-func spaNoRouteHandler(staticDir string) gin.HandlerFunc {
+// spaNoRouteHandler wraps the SPA handler so API routes get a JSON 404
+// instead of falling through to index.html.
+func spaNoRouteHandler(handler gin.HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// API routes should return JSON 404, not the SPA
-		if len(c.Request.URL.Path) >= 4 && c.Request.URL.Path[:4] == "/api" {
+		if strings.HasPrefix(c.Request.URL.Path, "/api") {
 			log.Printf("API route not found: %s %s", c.Request.Method, c.Request.URL.Path)
 			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 			return
 		}
-		// Serve index.html for all other routes (SPA client-side routing)
-		c.File(staticDir + "/index.html")
+		handler(c)
 	}
 }
 
@@ -121,13 +282,3 @@ func (w pgxWrap) Exec(ctx context.Context, sql string, args ...any) error {
 func (w pgxWrap) QueryRow(ctx context.Context, sql string, args ...any) interface{ Scan(dest ...any) error } {
 	return w.Pool.QueryRow(ctx, sql, args...)
 }
-
-func ensureSchema(ctx context.Context, p *pgxpool.Pool) {
-	_, _ = p.Exec(ctx, `create table if not exists app_user(
-	id bigserial primary key,
-	name text not null,
-	email text unique not null,
-	password_hash text not null,
-	created_at timestamptz default now()
-	)`)
-}
@@ -0,0 +1,10 @@
+// Package assets embeds the compiled SPA bundle into the binary. The CI
+// build pipeline places the frontend's build output under ./static (relative
+// to this package) before `go build` runs so it gets baked in; see
+// configureStaticFiles in cmd/api for the APP_STATIC_DIR dev-mode override.
+package assets
+
+import "embed"
+
+//go:embed all:static
+var Static embed.FS
@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migration is a single discovered version with its up/down SQL and a
+// checksum of the up script, used to detect drift in already-applied
+// migrations.
+type migration struct {
+	version  int64
+	name     string
+	up       string
+	down     string
+	checksum string
+}
+
+// load discovers every "<version>_<name>.up.sql" / ".down.sql" pair in fsys,
+// sorted by version.
+func load(fsys fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read migrations dir: %w", err)
+	}
+
+	byVersion := map[int64]*migration{}
+	for _, e := range entries {
+		version, rest, ok := splitVersion(e.Name())
+		if !ok {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", e.Name(), err)
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &migration{version: version}
+			byVersion[version] = mig
+		}
+
+		switch {
+		case strings.HasSuffix(rest, ".up.sql"):
+			mig.name = strings.TrimSuffix(rest, ".up.sql")
+			mig.up = string(data)
+			sum := sha256.Sum256(data)
+			mig.checksum = hex.EncodeToString(sum[:])
+		case strings.HasSuffix(rest, ".down.sql"):
+			mig.down = string(data)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.up == "" {
+			return nil, fmt.Errorf("migrate: version %d has a .down.sql but no .up.sql", mig.version)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// splitVersion parses a "0001_init.up.sql" style filename into its numeric
+// version (1) and the remainder after the version prefix ("init.up.sql").
+func splitVersion(filename string) (version int64, rest string, ok bool) {
+	idx := strings.IndexByte(filename, '_')
+	if idx < 0 {
+		return 0, "", false
+	}
+	version, err := strconv.ParseInt(filename[:idx], 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return version, filename[idx+1:], true
+}
@@ -0,0 +1,184 @@
+// Package migrate applies versioned, checksummed SQL migrations to
+// Postgres, recording what has already run in a schema_migrations table and
+// holding a Postgres advisory lock so concurrent instances don't race to
+// apply them on startup.
+package migrate
+
+import (
+	"context"
+	"embed"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+//go:embed *.up.sql *.down.sql
+var embeddedMigrations embed.FS
+
+// advisoryLockKey is an arbitrary, fixed key used to serialize migrations
+// across concurrent instances via pg_advisory_lock.
+const advisoryLockKey int64 = 0x7065647235 // "pedr5" as ASCII, chosen to be unlikely to collide
+
+// Migrator applies the embedded migrations against a database.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []migration
+	logger     *zap.Logger
+}
+
+// New loads the embedded migrations and returns a Migrator ready to run them
+// against pool, logging applied/rolled-back migrations through logger.
+func New(pool *pgxpool.Pool, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := load(embeddedMigrations)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations, logger: logger}, nil
+}
+
+// Up applies every migration not yet recorded in schema_migrations, in
+// version order, refusing to proceed if an already-applied migration's
+// checksum no longer matches what's embedded in the binary.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(tx pgx.Tx) error {
+		applied, err := appliedVersions(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if checksum, ok := applied[mig.version]; ok {
+				if checksum != mig.checksum {
+					return fmt.Errorf("migrate: checksum drift on %04d_%s: applied migration no longer matches the embedded source", mig.version, mig.name)
+				}
+				continue
+			}
+			if _, err := tx.Exec(ctx, mig.up); err != nil {
+				return fmt.Errorf("migrate: apply %04d_%s: %w", mig.version, mig.name, err)
+			}
+			if _, err := tx.Exec(ctx, `insert into schema_migrations(version, applied_at, checksum) values ($1, now(), $2)`, mig.version, mig.checksum); err != nil {
+				return fmt.Errorf("migrate: record %04d_%s: %w", mig.version, mig.name, err)
+			}
+			m.logger.Info("migrate: applied", zap.Int64("version", mig.version), zap.String("name", mig.name))
+		}
+		return nil
+	})
+}
+
+// Down rolls back the single most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(tx pgx.Tx) error {
+		applied, err := appliedVersions(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		var latest *migration
+		for i := range m.migrations {
+			if _, ok := applied[m.migrations[i].version]; ok {
+				latest = &m.migrations[i]
+			}
+		}
+		if latest == nil {
+			m.logger.Info("migrate: nothing to roll back")
+			return nil
+		}
+		if latest.down == "" {
+			return fmt.Errorf("migrate: %04d_%s has no .down.sql", latest.version, latest.name)
+		}
+		if _, err := tx.Exec(ctx, latest.down); err != nil {
+			return fmt.Errorf("migrate: roll back %04d_%s: %w", latest.version, latest.name, err)
+		}
+		if _, err := tx.Exec(ctx, `delete from schema_migrations where version = $1`, latest.version); err != nil {
+			return fmt.Errorf("migrate: unrecord %04d_%s: %w", latest.version, latest.name, err)
+		}
+		m.logger.Info("migrate: rolled back", zap.Int64("version", latest.version), zap.String("name", latest.name))
+		return nil
+	})
+}
+
+// Status reports, for every discovered migration, whether it has been
+// applied and whether its checksum still matches.
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+	Drift   bool
+}
+
+// Status reports the applied/drift state of every discovered migration.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(tx pgx.Tx) error {
+		applied, err := appliedVersions(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			checksum, ok := applied[mig.version]
+			statuses = append(statuses, Status{
+				Version: mig.version,
+				Name:    mig.name,
+				Applied: ok,
+				Drift:   ok && checksum != mig.checksum,
+			})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+func appliedVersions(ctx context.Context, tx pgx.Tx) (map[int64]string, error) {
+	if _, err := tx.Exec(ctx, `create table if not exists schema_migrations(
+		version bigint primary key,
+		applied_at timestamptz not null default now(),
+		checksum text not null
+	)`); err != nil {
+		return nil, fmt.Errorf("migrate: create schema_migrations: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `select version, checksum from schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]string{}
+	for rows.Next() {
+		var version int64
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("migrate: scan schema_migrations: %w", err)
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+// withLock runs fn inside a transaction held on a connection that also holds
+// a Postgres advisory lock, so concurrent instances booting at the same time
+// don't race to apply migrations.
+func (m *Migrator) withLock(ctx context.Context, fn func(pgx.Tx) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `select pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return fmt.Errorf("migrate: acquire advisory lock: %w", err)
+	}
+	defer conn.Exec(ctx, `select pg_advisory_unlock($1)`, advisoryLockKey)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,220 @@
+// Package deploy implements a token-gated admin endpoint for hot-swapping
+// the SPA bundle served by internal/static without redeploying the binary.
+package deploy
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pedrohdcosta/projetoPortifolio/Portifolio_back/internal/static"
+)
+
+// maxReleases is the number of past releases kept on disk for rollback; older
+// ones are pruned after every successful deploy.
+const maxReleases = 5
+
+// Manager extracts uploaded frontend bundles into versioned release
+// directories and hot-swaps the SPA handler serving them.
+type Manager struct {
+	releasesDir string
+	token       string
+	spa         *static.Reloadable
+}
+
+// NewManager returns a Manager that stores releases under releasesDir and
+// requires bearer token adminToken on every request. An empty adminToken
+// disables the endpoints entirely.
+func NewManager(releasesDir, adminToken string, spa *static.Reloadable) *Manager {
+	return &Manager{releasesDir: releasesDir, token: adminToken, spa: spa}
+}
+
+// RegisterRoutes wires the deploy/rollback endpoints onto r.
+func (m *Manager) RegisterRoutes(r *gin.Engine) {
+	admin := r.Group("/api/admin/frontend", m.requireToken)
+	admin.POST("/deploy", m.deploy)
+	admin.GET("/releases", m.listReleases)
+	admin.POST("/releases", m.activateRelease)
+}
+
+func (m *Manager) requireToken(c *gin.Context) {
+	if m.token == "" {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "frontend deploy is disabled"})
+		return
+	}
+	const prefix = "Bearer "
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, prefix) ||
+		subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, prefix)), []byte(m.token)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+		return
+	}
+}
+
+// deploy accepts a multipart "bundle" field containing a ZIP or tar.gz of a
+// built frontend, extracts it into a new versioned release directory, and
+// activates it immediately.
+func (m *Manager) deploy(c *gin.Context) {
+	file, err := c.FormFile("bundle")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": `missing "bundle" form file`})
+		return
+	}
+
+	extract, err := extractorFor(file.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version := strconv.FormatInt(time.Now().UnixNano(), 10)
+	dest := filepath.Join(m.releasesDir, version)
+	if err := extract(file, dest); err != nil {
+		_ = os.RemoveAll(dest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := m.activate(version); err != nil {
+		_ = os.RemoveAll(dest)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	m.pruneOldReleases()
+
+	c.JSON(http.StatusOK, gin.H{"version": version})
+}
+
+// extractorFor picks the archive format to unpack based on the uploaded
+// bundle's file extension, accepting the ZIP and tar.gz forms the upload
+// endpoint documents.
+func extractorFor(filename string) (func(*multipart.FileHeader, string) error, error) {
+	switch {
+	case strings.HasSuffix(filename, ".zip"):
+		return extractZip, nil
+	case strings.HasSuffix(filename, ".tar.gz"), strings.HasSuffix(filename, ".tgz"):
+		return extractTarGz, nil
+	default:
+		return nil, fmt.Errorf("deploy: unsupported bundle format %q, want .zip or .tar.gz", filename)
+	}
+}
+
+// listReleases reports every release kept on disk and which one is live.
+func (m *Manager) listReleases(c *gin.Context) {
+	versions, err := m.releaseVersions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	current, _ := os.Readlink(m.currentLink())
+	c.JSON(http.StatusOK, gin.H{"releases": versions, "current": filepath.Base(current)})
+}
+
+// activateRelease rolls the live SPA back (or forward) to an already
+// extracted release, identified by its version.
+func (m *Manager) activateRelease(c *gin.Context) {
+	var body struct {
+		Version string `json:"version" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !m.isKnownRelease(body.Version) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("deploy: unknown release %q", body.Version)})
+		return
+	}
+	if err := m.activate(body.Version); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"version": body.Version})
+}
+
+// isKnownRelease reports whether version names one of the directories
+// releaseVersions would list, so activateRelease can't be used to point the
+// live SPA (and its "current" symlink) at an arbitrary path via traversal.
+func (m *Manager) isKnownRelease(version string) bool {
+	versions, err := m.releaseVersions()
+	if err != nil {
+		return false
+	}
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) currentLink() string { return filepath.Join(m.releasesDir, "current") }
+
+// activate builds a static.Handler for the given release, hot-swaps it into
+// the live Reloadable, and repoints the "current" symlink to match.
+func (m *Manager) activate(version string) error {
+	releaseDir := filepath.Join(m.releasesDir, version)
+	if info, err := os.Stat(releaseDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("deploy: unknown release %q", version)
+	}
+
+	handler, err := static.Handler(os.DirFS(releaseDir))
+	if err != nil {
+		return fmt.Errorf("deploy: %w", err)
+	}
+
+	tmpLink := m.currentLink() + ".tmp"
+	_ = os.Remove(tmpLink)
+	if err := os.Symlink(version, tmpLink); err != nil {
+		return fmt.Errorf("deploy: symlink: %w", err)
+	}
+	if err := os.Rename(tmpLink, m.currentLink()); err != nil {
+		return fmt.Errorf("deploy: activate symlink: %w", err)
+	}
+
+	m.spa.Swap(handler)
+	return nil
+}
+
+func (m *Manager) releaseVersions() ([]string, error) {
+	entries, err := os.ReadDir(m.releasesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() {
+			if _, err := strconv.ParseInt(e.Name(), 10, 64); err == nil {
+				versions = append(versions, e.Name())
+			}
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// pruneOldReleases keeps only the newest maxReleases directories, never
+// removing whichever one is currently live.
+func (m *Manager) pruneOldReleases() {
+	versions, err := m.releaseVersions()
+	if err != nil || len(versions) <= maxReleases {
+		return
+	}
+	current, _ := os.Readlink(m.currentLink())
+	for _, v := range versions[:len(versions)-maxReleases] {
+		if v == filepath.Base(current) {
+			continue
+		}
+		_ = os.RemoveAll(filepath.Join(m.releasesDir, v))
+	}
+}
@@ -0,0 +1,76 @@
+package deploy
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// extractTarGz unpacks a gzip-compressed tar archive into dest, rejecting
+// entries that would escape dest via path traversal (the same safeJoin check
+// extractZip uses for the classic zip-slip attack).
+func extractTarGz(file *multipart.FileHeader, dest string) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("deploy: open upload: %w", err)
+	}
+	defer src.Close()
+
+	gr, err := gzip.NewReader(src)
+	if err != nil {
+		return fmt.Errorf("deploy: not a valid tar.gz archive: %w", err)
+	}
+	defer gr.Close()
+
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return fmt.Errorf("deploy: create release dir: %w", err)
+	}
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("deploy: not a valid tar.gz archive: %w", err)
+		}
+
+		target, err := safeJoin(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("deploy: %w", err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return fmt.Errorf("deploy: %w", err)
+			}
+			if err := extractTarFile(tr, target, hdr.FileInfo().Mode()); err != nil {
+				return fmt.Errorf("deploy: extract %q: %w", hdr.Name, err)
+			}
+		default:
+			// Symlinks, devices, etc. have no place in a frontend bundle.
+			continue
+		}
+	}
+}
+
+func extractTarFile(r io.Reader, target string, mode os.FileMode) error {
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, r)
+	return err
+}
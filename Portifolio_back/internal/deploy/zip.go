@@ -0,0 +1,90 @@
+package deploy
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extractZip buffers the uploaded archive to disk, then unpacks every entry
+// into dest, rejecting anything that would escape dest via path traversal
+// (the classic zip-slip attack).
+func extractZip(file *multipart.FileHeader, dest string) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("deploy: open upload: %w", err)
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp("", "frontend-deploy-*.zip")
+	if err != nil {
+		return fmt.Errorf("deploy: buffer upload: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		return fmt.Errorf("deploy: buffer upload: %w", err)
+	}
+
+	zr, err := zip.OpenReader(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("deploy: not a valid zip archive: %w", err)
+	}
+	defer zr.Close()
+
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return fmt.Errorf("deploy: create release dir: %w", err)
+	}
+
+	for _, f := range zr.File {
+		target, err := safeJoin(dest, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("deploy: %w", err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("deploy: %w", err)
+		}
+		if err := extractFile(f, target); err != nil {
+			return fmt.Errorf("deploy: extract %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// safeJoin joins dest and name, rejecting entries that would resolve outside
+// of dest.
+func safeJoin(dest, name string) (string, error) {
+	target := filepath.Join(dest, name)
+	if target != dest && !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("deploy: zip entry escapes destination: %q", name)
+	}
+	return target, nil
+}
+
+func extractFile(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, rc)
+	return err
+}
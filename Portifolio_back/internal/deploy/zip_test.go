@@ -0,0 +1,30 @@
+package deploy
+
+import "testing"
+
+func TestSafeJoinRejectsPathTraversal(t *testing.T) {
+	const dest = "/var/lib/app/releases/123"
+
+	cases := []struct {
+		name    string
+		entry   string
+		wantErr bool
+	}{
+		{"regular file", "index.html", false},
+		{"nested file", "assets/app.js", false},
+		{"parent traversal", "../../etc/passwd", true},
+		{"sibling-prefix collision", "../123-evil/passwd", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := safeJoin(dest, tc.entry)
+			if tc.wantErr && err == nil {
+				t.Fatalf("safeJoin(%q, %q) = nil error, want it rejected", dest, tc.entry)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("safeJoin(%q, %q) = %v, want no error", dest, tc.entry, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package httpmw
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// minGzipSize is the smallest response body worth paying the gzip CPU cost
+// for; smaller payloads are written through unencoded.
+const minGzipSize = 1024
+
+// Gzip compresses responses over minGzipSize for clients that advertise
+// gzip support, scoped to /api/* and the SPA's static assets. It
+// deliberately skips everything else — in particular /metrics, /healthz and
+// /readyz, whose tiny, frequently-polled bodies have nothing to gain from
+// compression.
+func Gzip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !shouldCompress(c.Request.URL.Path) || !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+			c.Next()
+			return
+		}
+
+		w := &gzipBufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		body := w.buf.Bytes()
+		if len(body) < minGzipSize {
+			_, _ = w.ResponseWriter.Write(body)
+			return
+		}
+
+		w.ResponseWriter.Header().Set("Content-Encoding", "gzip")
+		w.ResponseWriter.Header().Del("Content-Length")
+		gw := gzip.NewWriter(w.ResponseWriter)
+		_, _ = gw.Write(body)
+		_ = gw.Close()
+	}
+}
+
+// shouldCompress reports whether path serves the kind of compressible
+// payload (JSON API responses, the SPA shell, its JS/CSS assets) this
+// middleware was asked to cover.
+func shouldCompress(path string) bool {
+	switch {
+	case strings.HasPrefix(path, "/api/"):
+		return true
+	case strings.HasPrefix(path, "/assets/"):
+		return true
+	case path == "/" || path == "/index.html":
+		return true
+	default:
+		return false
+	}
+}
+
+// gzipBufferedWriter buffers the response body so Gzip can decide, once the
+// handler has finished, whether compressing it is worthwhile.
+type gzipBufferedWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *gzipBufferedWriter) Write(data []byte) (int, error) {
+	return w.buf.Write(data)
+}
+
+func (w *gzipBufferedWriter) WriteString(s string) (int, error) {
+	return w.buf.WriteString(s)
+}
@@ -0,0 +1,107 @@
+// Package logging provides structured JSON request logging, request-ID
+// propagation, and panic recovery for the Gin router.
+package logging
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// New builds a zap.Logger that emits one JSON object per line. level is
+// parsed from LOG_LEVEL (debug/info/warn/error, ...) and defaults to info
+// when empty or unrecognized.
+func New(level string) (*zap.Logger, error) {
+	cfg := zap.NewProductionConfig()
+	cfg.EncoderConfig.TimeKey = "ts"
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	lvl := zapcore.InfoLevel
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, err
+		}
+	}
+	cfg.Level = zap.NewAtomicLevelAt(lvl)
+
+	return cfg.Build()
+}
+
+// WithRequestID returns a context carrying requestID, retrievable with
+// RequestID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or "" if none is set.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestIDMiddleware reads X-Request-ID off the incoming request, or
+// generates one, and propagates it via the response header and
+// request.Context() so handlers, ensureSchema, and pgx queries can log
+// correlated errors.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-ID")
+		if id == "" {
+			id = uuid.NewString()
+		}
+		c.Writer.Header().Set("X-Request-ID", id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// Middleware emits one structured JSON log line per request. The client IP
+// is c.ClientIP(), which already honors X-Forwarded-For only when the
+// immediate peer is one of the proxies configured via TRUSTED_PROXIES /
+// r.SetTrustedProxies (see cmd/api's CORS/security-header middleware setup).
+func Middleware(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		userID, _ := c.Get("userID")
+
+		logger.Info("request",
+			zap.String("request_id", RequestID(c.Request.Context())),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.Any("user_id", userID),
+		)
+	}
+}
+
+// Recovery logs panics as structured fields, including the stack trace, and
+// responds with a generic 500 instead of crashing the process.
+func Recovery(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("panic recovered",
+					zap.String("request_id", RequestID(c.Request.Context())),
+					zap.Any("error", r),
+					zap.Stack("stack"),
+				)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			}
+		}()
+		c.Next()
+	}
+}
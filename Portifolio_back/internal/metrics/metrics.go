@@ -0,0 +1,144 @@
+// Package metrics exposes a Prometheus /metrics endpoint plus a Gin
+// middleware that records per-route request counts, latency, in-flight
+// requests, and response sizes, together with pgxpool connection stats.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// latencyBuckets spans 5ms to 10s, matching the operator-facing SLOs this
+// service is expected to meet.
+var latencyBuckets = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// Metrics holds the Prometheus collectors registered under a single
+// namespace/subsystem pair.
+type Metrics struct {
+	namespace string
+	subsystem string
+	registry  *prometheus.Registry
+
+	requestsTotal  *prometheus.CounterVec
+	requestLatency *prometheus.HistogramVec
+	responseSize   *prometheus.HistogramVec
+	inFlight       prometheus.Gauge
+}
+
+// Setup registers a fresh set of HTTP request collectors under namespace and
+// subsystem and returns the Metrics ready to produce middleware and a scrape
+// handler.
+func Setup(namespace, subsystem string) *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+
+	m := &Metrics{
+		namespace: namespace,
+		subsystem: subsystem,
+		registry:  registry,
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests, by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by route, method and status.",
+			Buckets:   latencyBuckets,
+		}, []string{"route", "method", "status"}),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "http_response_size_bytes",
+			Help:      "HTTP response size, by route, method and status.",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+		}, []string{"route", "method", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests currently being served.",
+		}),
+	}
+
+	registry.MustRegister(m.requestsTotal, m.requestLatency, m.responseSize, m.inFlight)
+	return m
+}
+
+// Middleware records request counts, latency, in-flight count, and response
+// size for every request it wraps. Register it before any routes whose
+// traffic should be measured.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		m.requestsTotal.WithLabelValues(route, c.Request.Method, status).Inc()
+		m.requestLatency.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+		m.responseSize.WithLabelValues(route, c.Request.Method, status).Observe(float64(c.Writer.Size()))
+	}
+}
+
+// RegisterPool adds gauges reporting pool.Stat() on every scrape: acquired,
+// idle and total connections, plus cumulative acquire wait time.
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: m.namespace, Subsystem: m.subsystem,
+			Name: "db_pool_acquired_conns",
+			Help: "Number of currently acquired connections in the pgx pool.",
+		}, func() float64 { return float64(pool.Stat().AcquiredConns()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: m.namespace, Subsystem: m.subsystem,
+			Name: "db_pool_idle_conns",
+			Help: "Number of currently idle connections in the pgx pool.",
+		}, func() float64 { return float64(pool.Stat().IdleConns()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: m.namespace, Subsystem: m.subsystem,
+			Name: "db_pool_total_conns",
+			Help: "Total number of connections currently in the pgx pool.",
+		}, func() float64 { return float64(pool.Stat().TotalConns()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: m.namespace, Subsystem: m.subsystem,
+			Name: "db_pool_acquire_wait_time_seconds",
+			Help: "Cumulative time spent waiting to acquire a connection from the pgx pool.",
+		}, func() float64 { return pool.Stat().AcquireDuration().Seconds() }),
+	)
+}
+
+// Handler returns the /metrics scrape handler. When token is non-empty, the
+// endpoint is gated behind HTTP basic auth (username "metrics").
+func (m *Metrics) Handler(token string) gin.HandlerFunc {
+	scrape := gin.WrapH(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	if token == "" {
+		return scrape
+	}
+
+	basicAuth := gin.BasicAuth(gin.Accounts{"metrics": token})
+	return func(c *gin.Context) {
+		basicAuth(c)
+		if c.IsAborted() {
+			return
+		}
+		scrape(c)
+	}
+}
@@ -0,0 +1,34 @@
+package static
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Reloadable wraps a gin.HandlerFunc behind an atomic pointer so the SPA
+// bundle being served can be swapped out at runtime (see internal/deploy)
+// without restarting the process or re-registering routes.
+type Reloadable struct {
+	current atomic.Pointer[gin.HandlerFunc]
+}
+
+// NewReloadable returns a Reloadable that serves handler until the next Swap.
+func NewReloadable(handler gin.HandlerFunc) *Reloadable {
+	r := &Reloadable{}
+	r.Swap(handler)
+	return r
+}
+
+// Swap atomically replaces the handler serving subsequent requests.
+func (r *Reloadable) Swap(handler gin.HandlerFunc) {
+	r.current.Store(&handler)
+}
+
+// Handler returns a gin.HandlerFunc that always delegates to whichever
+// handler is currently active.
+func (r *Reloadable) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		(*r.current.Load())(c)
+	}
+}
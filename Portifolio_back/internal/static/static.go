@@ -0,0 +1,157 @@
+// Package static serves a single-page application out of an fs.FS,
+// fingerprinting the built assets so they can be cached aggressively while
+// index.html itself is always revalidated.
+package static
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// assetRefPattern matches src="..."/href="..." references to local assets
+// inside index.html so they can be rewritten with a cache-busting hash.
+var assetRefPattern = regexp.MustCompile(`(src|href)="(/assets/[^"]+)"`)
+
+// fingerprint is the content hash discovered for a single embedded asset.
+type fingerprint struct {
+	hash    string
+	modTime time.Time
+}
+
+// spa serves a single-page application out of an fs.FS.
+type spa struct {
+	fsys      fs.FS
+	assets    map[string]fingerprint
+	index     []byte
+	indexHash string
+}
+
+// Handler walks fsys, computes a SHA-256 fingerprint for every file under
+// assets/, rewrites the asset references in index.html to include that
+// fingerprint as a "?v=" query param, and returns a gin.HandlerFunc that
+// serves the result.
+//
+// /assets/* responses carry a far-future Cache-Control plus a strong ETag;
+// index.html is served with Cache-Control: no-cache so clients always pick up
+// the latest fingerprinted asset URLs.
+func Handler(fsys fs.FS) (gin.HandlerFunc, error) {
+	s := &spa{fsys: fsys, assets: map[string]fingerprint{}}
+
+	err := fs.WalkDir(fsys, "assets", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		s.assets["/"+p] = fingerprint{hash: hex.EncodeToString(sum[:])[:16], modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("static: walk assets: %w", err)
+	}
+
+	index, err := fs.ReadFile(fsys, "index.html")
+	if err != nil {
+		return nil, fmt.Errorf("static: read index.html: %w", err)
+	}
+	s.index = s.rewriteIndex(index)
+	sum := sha256.Sum256(s.index)
+	s.indexHash = hex.EncodeToString(sum[:])
+
+	return s.serve, nil
+}
+
+// rewriteIndex appends "?v=<hash>" to every local /assets/* reference so
+// browsers fetch the new bundle the moment index.html changes.
+func (s *spa) rewriteIndex(index []byte) []byte {
+	return assetRefPattern.ReplaceAllFunc(index, func(m []byte) []byte {
+		sub := assetRefPattern.FindSubmatch(m)
+		attr, ref := string(sub[1]), string(sub[2])
+		fp, ok := s.assets[ref]
+		if !ok {
+			return m
+		}
+		return []byte(fmt.Sprintf(`%s="%s?v=%s"`, attr, ref, fp.hash))
+	})
+}
+
+// serve routes assets, the rewritten index.html, and any other root-level
+// file (favicon.ico, manifest.json, ...) out of the underlying fs.FS. Unknown
+// paths fall back to index.html so the SPA's client-side router can take
+// over.
+func (s *spa) serve(c *gin.Context) {
+	p := c.Request.URL.Path
+	switch {
+	case p == "/" || p == "/index.html":
+		s.serveIndex(c)
+	case strings.HasPrefix(p, "/assets/"):
+		s.serveAsset(c, p)
+	case fileExists(s.fsys, strings.TrimPrefix(p, "/")):
+		http.FileServer(http.FS(s.fsys)).ServeHTTP(c.Writer, c.Request)
+	default:
+		s.serveIndex(c)
+	}
+}
+
+func fileExists(fsys fs.FS, name string) bool {
+	if name == "" {
+		return false
+	}
+	info, err := fs.Stat(fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+func (s *spa) serveIndex(c *gin.Context) {
+	etag := `"` + s.indexHash + `"`
+	c.Header("Cache-Control", "no-cache")
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", s.index)
+}
+
+func (s *spa) serveAsset(c *gin.Context, p string) {
+	fp, ok := s.assets[p]
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	etag := `"` + fp.hash + `"`
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	data, err := fs.ReadFile(s.fsys, strings.TrimPrefix(p, "/"))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	http.ServeContent(c.Writer, c.Request, p, fp.modTime, bytes.NewReader(data))
+}